@@ -0,0 +1,28 @@
+package combat
+
+import "ecs"
+
+// Damage computes how much damage attacker deals to defender. It delegates to ecs.Damage, which
+// is the canonical formula shared with CombatSystem's AI-initiated attacks.
+func Damage(world *ecs.World, attacker, defender ecs.EntityID) int {
+	return ecs.Damage(world, attacker, defender)
+}
+
+// Attack resolves a bump-to-attack between attacker and defender: it applies Damage to the
+// defender's HitPoint, then runs DeathSystem so a defender dropping to zero immediately becomes
+// a corpse. It returns the damage dealt and whether the defender died as a result.
+func Attack(world *ecs.World, attacker, defender ecs.EntityID) (damage int, killed bool) {
+	hpComponent, ok := world.Get(defender, ecs.HitPoint)
+	if !ok {
+		return 0, false
+	}
+	hp := hpComponent.(ecs.HitPointComponent)
+
+	damage = Damage(world, attacker, defender)
+	hp.Hp -= damage
+	world.AddComponent(defender, ecs.HitPoint, hp)
+
+	ecs.DeathSystem{}.Update(world, 1)
+
+	return damage, hp.Hp <= 0
+}
@@ -0,0 +1,72 @@
+package combat
+
+import (
+	"ecs"
+	"testing"
+)
+
+func TestDamageSubtractsDefenseFlooredAtZero(t *testing.T) {
+	world := ecs.NewWorld()
+	attacker := world.NewEntity()
+	defender := world.NewEntity()
+
+	world.AddComponent(attacker, ecs.Attacker, ecs.AttackerComponent{Attack: 5, Defense: 0})
+	world.AddComponent(defender, ecs.Attacker, ecs.AttackerComponent{Attack: 0, Defense: 8})
+
+	if got := Damage(world, attacker, defender); got != 0 {
+		t.Fatalf("Damage() = %d, want 0 (defense exceeds attack)", got)
+	}
+
+	world.AddComponent(defender, ecs.Attacker, ecs.AttackerComponent{Attack: 0, Defense: 2})
+	if got := Damage(world, attacker, defender); got != 3 {
+		t.Fatalf("Damage() = %d, want 3", got)
+	}
+}
+
+func TestDamageWithNoAttackerComponentIsZero(t *testing.T) {
+	world := ecs.NewWorld()
+	attacker := world.NewEntity()
+	defender := world.NewEntity()
+
+	if got := Damage(world, attacker, defender); got != 0 {
+		t.Fatalf("Damage() = %d, want 0 for an attacker with no AttackerComponent", got)
+	}
+}
+
+func TestAttackAppliesDamageAndReportsKill(t *testing.T) {
+	world := ecs.NewWorld()
+	attacker := world.NewEntity()
+	defender := world.NewEntity()
+
+	world.AddComponent(attacker, ecs.Attacker, ecs.AttackerComponent{Attack: 5, Defense: 0})
+	world.AddComponent(defender, ecs.Attacker, ecs.AttackerComponent{Attack: 0, Defense: 0})
+	world.AddComponent(defender, ecs.HitPoint, ecs.HitPointComponent{Hp: 3, MaxHP: 3})
+
+	damage, killed := Attack(world, attacker, defender)
+
+	if damage != 5 {
+		t.Fatalf("Attack() damage = %d, want 5", damage)
+	}
+	if !killed {
+		t.Fatalf("Attack() killed = false, want true (3 HP - 5 damage <= 0)")
+	}
+
+	hpComponent, _ := world.Get(defender, ecs.HitPoint)
+	if hp := hpComponent.(ecs.HitPointComponent).Hp; hp != -2 {
+		t.Fatalf("defender HP = %d, want -2", hp)
+	}
+}
+
+func TestAttackOnMissingDefenderHitPointIsNoop(t *testing.T) {
+	world := ecs.NewWorld()
+	attacker := world.NewEntity()
+	defender := world.NewEntity()
+
+	world.AddComponent(attacker, ecs.Attacker, ecs.AttackerComponent{Attack: 5, Defense: 0})
+
+	damage, killed := Attack(world, attacker, defender)
+
+	if damage != 0 || killed {
+		t.Fatalf("Attack() = (%d, %v), want (0, false) when defender has no HitPoint", damage, killed)
+	}
+}
@@ -0,0 +1,85 @@
+package dungeon
+
+// This package relies on gamemap already exposing TileStairsDown/TileStairsUp tile types and
+// GenerateCavernAtDepth placing at least one of each - those are gamemap-side changes outside
+// this package's tree and are assumed to exist rather than re-implemented here.
+import "gamemap"
+
+// Dungeon owns every level the player has visited, indexed by depth, and tracks which one is
+// currently active. Levels are generated lazily - the first time the player descends to a given
+// depth a new gamemap.Map is built (with generation parameters scaled to that depth); visiting
+// the same depth again just returns the map that was already there, Explored tiles and all.
+type Dungeon struct {
+	Levels []*gamemap.Map
+	Depth  int
+	Width  int
+	Height int
+}
+
+// NewDungeon creates an empty Dungeon. Call Descend once to generate and enter the first level.
+func NewDungeon(width, height int) *Dungeon {
+	return &Dungeon{
+		Levels: make([]*gamemap.Map, 0),
+		Depth:  -1,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// CurrentLevel returns the gamemap.Map the player currently occupies.
+func (d *Dungeon) CurrentLevel() *gamemap.Map {
+	return d.Levels[d.Depth]
+}
+
+// Descend moves the dungeon to the next depth. If that depth has never been visited, a new level
+// is generated (scaled to the new depth) and the player enters at its upstairs tile; otherwise
+// the previously-generated level is returned as-is. The returned coordinates are where the
+// player should be placed. firstVisit is true when the level was just generated, so the caller
+// knows to populate it with fresh monsters.
+func (d *Dungeon) Descend() (level *gamemap.Map, entryX, entryY int, firstVisit bool) {
+	d.Depth++
+
+	if d.Depth < len(d.Levels) {
+		level = d.Levels[d.Depth]
+		return level, level.StairsUpX, level.StairsUpY, false
+	}
+
+	level = &gamemap.Map{Width: d.Width, Height: d.Height}
+	level.InitializeMap()
+
+	entryX, entryY = level.GenerateCavernAtDepth(d.Depth)
+	level.StairsUpX, level.StairsUpY = entryX, entryY
+	level.StairsDownX, level.StairsDownY = findStairsDown(level)
+
+	d.Levels = append(d.Levels, level)
+
+	return level, entryX, entryY, true
+}
+
+// findStairsDown locates the TileStairsDown tile GenerateCavernAtDepth placed on level, so
+// Ascend has somewhere to put the player back down when they return. Returns (0, 0) if the level
+// somehow has no downstairs.
+func findStairsDown(level *gamemap.Map) (x, y int) {
+	for tx := 0; tx < level.Width; tx++ {
+		for ty := 0; ty < level.Height; ty++ {
+			if level.Tiles[tx][ty].TileType == gamemap.TileStairsDown {
+				return tx, ty
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+// Ascend moves the dungeon back up to the previous depth, which must already have been
+// generated, and places the player on its downstairs tile.
+func (d *Dungeon) Ascend() (level *gamemap.Map, entryX, entryY int, ok bool) {
+	if d.Depth == 0 {
+		return nil, 0, 0, false
+	}
+
+	d.Depth--
+	level = d.Levels[d.Depth]
+
+	return level, level.StairsDownX, level.StairsDownY, true
+}
@@ -87,7 +87,6 @@ func (r RandomMovementComponent) IsAIComponent() bool {
 
 // Basic Melee Attack AI Component
 type BasicMeleeAIComponent struct {
-	target *GameEntity
 }
 
 func (b BasicMeleeAIComponent) IsAIComponent() bool {
@@ -114,4 +113,37 @@ type KillableComponent struct {
 
 func (k KillableComponent) IsAIComponent() bool {
 	return false
+}
+
+// Level Component - records which dungeon depth an entity belongs to, so systems that act on
+// every entity of a given shape (movement, AI, rendering) can restrict themselves to the level
+// the player is currently on.
+type LevelComponent struct {
+	Depth int
+}
+
+func (l LevelComponent) IsAIComponent() bool {
+	return false
+}
+
+// Move Intent Component - a transient component, added for a single turn to request that
+// MovementSystem attempt to move an entity by (DX, DY). Systems that service it remove it once
+// it has been handled.
+type MoveIntentComponent struct {
+	DX int
+	DY int
+}
+
+func (m MoveIntentComponent) IsAIComponent() bool {
+	return false
+}
+
+// Attack Intent Component - a transient component requesting that CombatSystem resolve an
+// attack made by this entity against Defender.
+type AttackIntentComponent struct {
+	Defender EntityID
+}
+
+func (a AttackIntentComponent) IsAIComponent() bool {
+	return false
 }
\ No newline at end of file
@@ -0,0 +1,24 @@
+package ecs
+
+import "encoding/gob"
+
+// Component is an interface, so encoding/gob needs every concrete type that might be stored
+// behind it registered up front - otherwise a save file containing, say, a BlockingComponent
+// fails to encode with "type not registered for interface".
+func init() {
+	gob.Register(PlayerComponent{})
+	gob.Register(PositionComponent{})
+	gob.Register(AppearanceComponent{})
+	gob.Register(MovementComponent{})
+	gob.Register(ControllableComponent{})
+	gob.Register(HitPointComponent{})
+	gob.Register(AttackerComponent{})
+	gob.Register(BlockingComponent{})
+	gob.Register(RandomMovementComponent{})
+	gob.Register(BasicMeleeAIComponent{})
+	gob.Register(ReproducesComponent{})
+	gob.Register(KillableComponent{})
+	gob.Register(MoveIntentComponent{})
+	gob.Register(AttackIntentComponent{})
+	gob.Register(LevelComponent{})
+}
@@ -0,0 +1,51 @@
+package ecs
+
+import (
+	"gamemap"
+	"testing"
+)
+
+func newTestMap(width, height, depth int) (*gamemap.Map, int, int) {
+	level := &gamemap.Map{Width: width, Height: height}
+	level.InitializeMap()
+
+	entryX, entryY := level.GenerateCavernAtDepth(depth)
+
+	return level, entryX, entryY
+}
+
+func TestNextStepTowardsSamePointIsNotOK(t *testing.T) {
+	level, entryX, entryY := newTestMap(40, 40, 1)
+
+	_, _, ok := nextStepTowards(level, entryX, entryY, entryX, entryY)
+	if ok {
+		t.Fatalf("nextStepTowards from a tile to itself should report ok=false")
+	}
+}
+
+func TestNextStepTowardsStepsOntoAnOpenNeighbor(t *testing.T) {
+	level, entryX, entryY := newTestMap(40, 40, 1)
+
+	directions := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	var neighborX, neighborY int
+	found := false
+	for _, direction := range directions {
+		nx, ny := entryX+direction[0], entryY+direction[1]
+		if !level.IsBlocked(nx, ny) {
+			neighborX, neighborY = nx, ny
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Skip("generated cavern left the entry tile fully enclosed, nothing to path towards")
+	}
+
+	x, y, ok := nextStepTowards(level, entryX, entryY, neighborX, neighborY)
+	if !ok {
+		t.Fatalf("expected a path from entry to its open neighbor")
+	}
+	if x != neighborX || y != neighborY {
+		t.Fatalf("nextStepTowards = (%d, %d), want (%d, %d)", x, y, neighborX, neighborY)
+	}
+}
@@ -0,0 +1,306 @@
+package ecs
+
+import (
+	blt "bearlibterminal"
+	"camera"
+	"gamemap"
+	"math/rand"
+)
+
+// System is anything that operates on a slice of a World's entities once per turn. dt is the
+// number of turns that have elapsed since the last Update - for this turn-based game that is
+// almost always 1, but it is threaded through so systems can be skipped/batched later without
+// changing the interface.
+type System interface {
+	Update(world *World, dt int)
+}
+
+// onDepth reports whether an entity's LevelComponent matches depth. Entities with no
+// LevelComponent at all are treated as depth-less and always match, so systems keep working in
+// saves/tests that never assign one.
+func onDepth(world *World, id EntityID, depth int) bool {
+	levelComponent, ok := world.Get(id, Level)
+	if !ok {
+		return true
+	}
+
+	return levelComponent.(LevelComponent).Depth == depth
+}
+
+// MovementSystem moves entities that are Controllable and carry a MoveIntentComponent, so long
+// as the destination tile is not blocked on the map.
+type MovementSystem struct {
+	GameMap *gamemap.Map
+	Depth   int
+}
+
+func (s MovementSystem) Update(world *World, dt int) {
+	for _, id := range world.Query(Position, Movement, Controllable) {
+		if !onDepth(world, id, s.Depth) {
+			continue
+		}
+
+		intent, ok := world.Get(id, MoveIntent)
+		if !ok {
+			continue
+		}
+
+		moveIntent := intent.(MoveIntentComponent)
+		positionComponent, _ := world.Get(id, Position)
+		pos := positionComponent.(PositionComponent)
+
+		destX, destY := pos.X+moveIntent.DX, pos.Y+moveIntent.DY
+		if !s.GameMap.IsBlocked(destX, destY) {
+			pos.X, pos.Y = destX, destY
+			world.AddComponent(id, Position, pos)
+		}
+
+		world.RemoveComponent(id, MoveIntent)
+	}
+}
+
+// RandomMovementSystem wanders entities that have RandomMovement one step in a random direction
+// each turn, respecting map collisions.
+type RandomMovementSystem struct {
+	GameMap *gamemap.Map
+	Depth   int
+}
+
+func (s RandomMovementSystem) Update(world *World, dt int) {
+	directions := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for _, id := range world.Query(Position, RandomMovement) {
+		if !onDepth(world, id, s.Depth) {
+			continue
+		}
+
+		positionComponent, _ := world.Get(id, Position)
+		pos := positionComponent.(PositionComponent)
+
+		direction := directions[rand.Intn(len(directions))]
+		destX, destY := pos.X+direction[0], pos.Y+direction[1]
+
+		if !s.GameMap.IsBlocked(destX, destY) {
+			pos.X, pos.Y = destX, destY
+			world.AddComponent(id, Position, pos)
+		}
+	}
+}
+
+// MeleeAISystem steps every BasicMeleeAI entity one tile closer to the player each turn. Once an
+// attacker is adjacent to the player it raises an AttackIntent instead of moving into them.
+type MeleeAISystem struct {
+	GameMap *gamemap.Map
+	Depth   int
+}
+
+func (s MeleeAISystem) Update(world *World, dt int) {
+	playerIDs := world.Query(Position, Player)
+	if len(playerIDs) == 0 {
+		return
+	}
+	playerID := playerIDs[0]
+
+	playerPositionComponent, _ := world.Get(playerID, Position)
+	playerPos := playerPositionComponent.(PositionComponent)
+
+	for _, id := range world.Query(Position, BasicMeleeAI) {
+		if !onDepth(world, id, s.Depth) {
+			continue
+		}
+
+		positionComponent, _ := world.Get(id, Position)
+		pos := positionComponent.(PositionComponent)
+
+		destX, destY, ok := nextStepTowards(s.GameMap, pos.X, pos.Y, playerPos.X, playerPos.Y)
+		if !ok {
+			continue
+		}
+
+		if destX == playerPos.X && destY == playerPos.Y {
+			world.AddComponent(id, AttackIntent, AttackIntentComponent{Defender: playerID})
+			continue
+		}
+
+		if !s.GameMap.IsBlocked(destX, destY) {
+			pos.X, pos.Y = destX, destY
+			world.AddComponent(id, Position, pos)
+		}
+	}
+}
+
+// bfsNode tracks a tile visited during nextStepTowards's breadth-first search, along with the
+// first step taken away from the origin to reach it - so once the target is found we already
+// know which direction to move this turn without having to walk the path back.
+type bfsNode struct {
+	x, y         int
+	firstX, firstY int
+}
+
+// nextStepTowards finds the shortest path from (fromX, fromY) to (toX, toY) across gameMap with
+// a breadth-first search, and returns the first tile to step onto along that path. ok is false if
+// no path exists.
+func nextStepTowards(gameMap *gamemap.Map, fromX, fromY, toX, toY int) (x, y int, ok bool) {
+	if fromX == toX && fromY == toY {
+		return fromX, fromY, false
+	}
+
+	visited := map[[2]int]bool{{fromX, fromY}: true}
+	queue := []bfsNode{{x: fromX, y: fromY, firstX: fromX, firstY: fromY}}
+
+	directions := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, direction := range directions {
+			nx, ny := current.x+direction[0], current.y+direction[1]
+
+			if nx == toX && ny == toY {
+				firstX, firstY := current.firstX, current.firstY
+				if current.x == fromX && current.y == fromY {
+					firstX, firstY = nx, ny
+				}
+				return firstX, firstY, true
+			}
+
+			if visited[[2]int{nx, ny}] || gameMap.IsBlocked(nx, ny) {
+				continue
+			}
+
+			visited[[2]int{nx, ny}] = true
+			firstX, firstY := current.firstX, current.firstY
+			if current.x == fromX && current.y == fromY {
+				firstX, firstY = nx, ny
+			}
+			queue = append(queue, bfsNode{x: nx, y: ny, firstX: firstX, firstY: firstY})
+		}
+	}
+
+	return 0, 0, false
+}
+
+// Damage computes how much damage attacker deals to defender using the standard formula: the
+// attacker's Attack stat minus the defender's Defense stat, floored at zero. An attacker with no
+// AttackerComponent deals no damage. This is the single source of truth for the formula - both
+// CombatSystem (AI-initiated attacks) and package combat (player bump attacks) call through it.
+func Damage(world *World, attacker, defender EntityID) int {
+	attackerComponent, ok := world.Get(attacker, Attacker)
+	if !ok {
+		return 0
+	}
+	attackerStats := attackerComponent.(AttackerComponent)
+
+	defense := 0
+	if defenderComponent, ok := world.Get(defender, Attacker); ok {
+		defense = defenderComponent.(AttackerComponent).Defense
+	}
+
+	damage := attackerStats.Attack - defense
+	if damage < 0 {
+		damage = 0
+	}
+
+	return damage
+}
+
+// CombatSystem resolves AttackIntent components raised against entities, applying damage to
+// HitPoint based on the attacker's AttackerComponent and the defender's. If Log is set, it is
+// called with the outcome of each attack so the caller can report it (e.g. to a message log).
+type CombatSystem struct {
+	Log func(attacker, defender EntityID, damage int)
+}
+
+func (s CombatSystem) Update(world *World, dt int) {
+	for _, attackerID := range world.Query(Attacker, AttackIntent) {
+		intentComponent, _ := world.Get(attackerID, AttackIntent)
+		intent := intentComponent.(AttackIntentComponent)
+
+		hpComponent, ok := world.Get(intent.Defender, HitPoint)
+		if !ok {
+			world.RemoveComponent(attackerID, AttackIntent)
+			continue
+		}
+		hp := hpComponent.(HitPointComponent)
+
+		damage := Damage(world, attackerID, intent.Defender)
+
+		hp.Hp -= damage
+		world.AddComponent(intent.Defender, HitPoint, hp)
+		world.RemoveComponent(attackerID, AttackIntent)
+
+		if s.Log != nil {
+			s.Log(attackerID, intent.Defender, damage)
+		}
+	}
+}
+
+// DeathSystem sweeps every Killable entity whose HitPoint has reached zero, turning it into a
+// corpse - its Appearance becomes the one described by KillableComponent, it stops Blocking
+// movement, and any AI components are stripped so it no longer acts.
+type DeathSystem struct{}
+
+func (s DeathSystem) Update(world *World, dt int) {
+	for _, id := range world.Query(HitPoint, Killable) {
+		hpComponent, _ := world.Get(id, HitPoint)
+		hp := hpComponent.(HitPointComponent)
+
+		if hp.Hp > 0 {
+			continue
+		}
+
+		killableComponent, _ := world.Get(id, Killable)
+		killable := killableComponent.(KillableComponent)
+
+		appearanceComponent, _ := world.Get(id, Appearance)
+		appearance := appearanceComponent.(AppearanceComponent)
+		appearance.Character = killable.Character
+		appearance.Color = killable.Color
+		appearance.Name = killable.Name
+		world.AddComponent(id, Appearance, appearance)
+
+		world.RemoveComponent(id, Blocking)
+		world.RemoveComponent(id, RandomMovement)
+		world.RemoveComponent(id, BasicMeleeAI)
+	}
+}
+
+// RenderSystem draws every entity that has a Position and an Appearance, replacing the old
+// field-poking renderEntities. Entities are drawn in Layer order so higher layers (e.g. the
+// player) are painted over lower ones (e.g. corpses, items).
+type RenderSystem struct {
+	GameMap    *gamemap.Map
+	GameCamera *camera.GameCamera
+	Depth      int
+}
+
+func (s RenderSystem) Update(world *World, dt int) {
+	ids := world.Query(Position, Appearance)
+
+	for layer := 0; layer <= 1; layer++ {
+		for _, id := range ids {
+			if !onDepth(world, id, s.Depth) {
+				continue
+			}
+
+			appearanceComponent, _ := world.Get(id, Appearance)
+			appearance := appearanceComponent.(AppearanceComponent)
+			if appearance.Layer != layer {
+				continue
+			}
+
+			positionComponent, _ := world.Get(id, Position)
+			pos := positionComponent.(PositionComponent)
+
+			if !s.GameMap.Tiles[pos.X][pos.Y].Visible {
+				continue
+			}
+
+			mapX, mapY := s.GameCamera.ToCameraCoordinates(pos.X, pos.Y)
+			blt.Layer(appearance.Layer)
+			blt.Color(blt.ColorFromName(appearance.Color))
+			blt.Print(mapX, mapY, appearance.Character)
+		}
+	}
+}
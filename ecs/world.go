@@ -0,0 +1,170 @@
+package ecs
+
+// EntityID uniquely identifies an entity within a World. Entities themselves are nothing
+// more than an ID - all state lives in the component storage below.
+type EntityID int
+
+// ComponentType tags which bucket of component storage a given Component belongs in.
+type ComponentType int
+
+const (
+	Player ComponentType = iota
+	Position
+	Appearance
+	Movement
+	Controllable
+	HitPoint
+	Attacker
+	Blocking
+	RandomMovement
+	BasicMeleeAI
+	Reproduces
+	Killable
+	MoveIntent
+	AttackIntent
+	Level
+)
+
+// World owns every entity and its components. Components are stored per-type, keyed by
+// EntityID, so systems can cheaply query for the set of entities that have a particular
+// combination of components.
+type World struct {
+	nextID     EntityID
+	components map[ComponentType]map[EntityID]Component
+}
+
+// NewWorld creates an empty World, ready to have entities registered with it.
+func NewWorld() *World {
+	return &World{
+		nextID:     0,
+		components: make(map[ComponentType]map[EntityID]Component),
+	}
+}
+
+// NewEntity allocates and returns a fresh EntityID. It carries no components until some are
+// added with AddComponent.
+func (w *World) NewEntity() EntityID {
+	id := w.nextID
+	w.nextID++
+	return id
+}
+
+// RemoveEntity strips every component from an entity, effectively deleting it from the World.
+func (w *World) RemoveEntity(id EntityID) {
+	for _, bucket := range w.components {
+		delete(bucket, id)
+	}
+}
+
+// AddComponent attaches a Component to an entity under the given ComponentType.
+func (w *World) AddComponent(id EntityID, componentType ComponentType, component Component) {
+	bucket, ok := w.components[componentType]
+	if !ok {
+		bucket = make(map[EntityID]Component)
+		w.components[componentType] = bucket
+	}
+
+	bucket[id] = component
+}
+
+// RemoveComponent detaches a single component from an entity, leaving the rest of the entity
+// intact.
+func (w *World) RemoveComponent(id EntityID, componentType ComponentType) {
+	if bucket, ok := w.components[componentType]; ok {
+		delete(bucket, id)
+	}
+}
+
+// Get looks up a single component for an entity. The second return value reports whether the
+// entity actually carries that component.
+func (w *World) Get(id EntityID, componentType ComponentType) (Component, bool) {
+	bucket, ok := w.components[componentType]
+	if !ok {
+		return nil, false
+	}
+
+	component, ok := bucket[id]
+	return component, ok
+}
+
+// NextID returns the EntityID that will be handed out by the next call to NewEntity. Combined
+// with SetNextID, this lets a save/load system restore the World's id counter.
+func (w *World) NextID() EntityID {
+	return w.nextID
+}
+
+// SetNextID overrides the World's id counter, used when restoring a World from a save file.
+func (w *World) SetNextID(id EntityID) {
+	w.nextID = id
+}
+
+// AllEntities returns every EntityID that carries at least one component, deduplicated, in no
+// particular order.
+func (w *World) AllEntities() []EntityID {
+	seen := make(map[EntityID]bool)
+	for _, bucket := range w.components {
+		for id := range bucket {
+			seen[id] = true
+		}
+	}
+
+	ids := make([]EntityID, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Components returns every component attached to an entity, keyed by ComponentType. It is used
+// to snapshot an entity for serialization.
+func (w *World) Components(id EntityID) map[ComponentType]Component {
+	components := make(map[ComponentType]Component)
+	for componentType, bucket := range w.components {
+		if component, ok := bucket[id]; ok {
+			components[componentType] = component
+		}
+	}
+
+	return components
+}
+
+// Restore replaces the World's entire component storage with the given entities, as produced by
+// repeated calls to Components. It is used to rebuild a World from a save file.
+func (w *World) Restore(entities map[EntityID]map[ComponentType]Component) {
+	w.components = make(map[ComponentType]map[EntityID]Component)
+
+	for id, components := range entities {
+		for componentType, component := range components {
+			w.AddComponent(id, componentType, component)
+		}
+	}
+}
+
+// Query returns every EntityID that carries all of the given component types.
+func (w *World) Query(componentTypes ...ComponentType) []EntityID {
+	matches := make([]EntityID, 0)
+
+	if len(componentTypes) == 0 {
+		return matches
+	}
+
+	base, ok := w.components[componentTypes[0]]
+	if !ok {
+		return matches
+	}
+
+	rest := componentTypes[1:]
+
+entities:
+	for id := range base {
+		for _, componentType := range rest {
+			if _, ok := w.components[componentType][id]; !ok {
+				continue entities
+			}
+		}
+		matches = append(matches, id)
+	}
+
+	return matches
+}
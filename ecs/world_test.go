@@ -0,0 +1,83 @@
+package ecs
+
+import "testing"
+
+func TestAddComponentAndGet(t *testing.T) {
+	world := NewWorld()
+	id := world.NewEntity()
+
+	world.AddComponent(id, Position, PositionComponent{X: 3, Y: 4})
+
+	component, ok := world.Get(id, Position)
+	if !ok {
+		t.Fatalf("expected entity %d to carry a Position component", id)
+	}
+
+	pos := component.(PositionComponent)
+	if pos.X != 3 || pos.Y != 4 {
+		t.Fatalf("got PositionComponent{%d, %d}, want {3, 4}", pos.X, pos.Y)
+	}
+}
+
+func TestGetMissingComponent(t *testing.T) {
+	world := NewWorld()
+	id := world.NewEntity()
+
+	if _, ok := world.Get(id, Position); ok {
+		t.Fatalf("expected entity %d to have no Position component", id)
+	}
+}
+
+func TestRemoveComponent(t *testing.T) {
+	world := NewWorld()
+	id := world.NewEntity()
+	world.AddComponent(id, Blocking, BlockingComponent{})
+
+	world.RemoveComponent(id, Blocking)
+
+	if _, ok := world.Get(id, Blocking); ok {
+		t.Fatalf("expected Blocking component to be removed from entity %d", id)
+	}
+}
+
+func TestRemoveEntityStripsAllComponents(t *testing.T) {
+	world := NewWorld()
+	id := world.NewEntity()
+	world.AddComponent(id, Position, PositionComponent{X: 1, Y: 1})
+	world.AddComponent(id, Blocking, BlockingComponent{})
+
+	world.RemoveEntity(id)
+
+	if _, ok := world.Get(id, Position); ok {
+		t.Fatalf("expected Position component to be gone after RemoveEntity")
+	}
+	if _, ok := world.Get(id, Blocking); ok {
+		t.Fatalf("expected Blocking component to be gone after RemoveEntity")
+	}
+}
+
+func TestQueryRequiresAllComponentTypes(t *testing.T) {
+	world := NewWorld()
+
+	both := world.NewEntity()
+	world.AddComponent(both, Position, PositionComponent{})
+	world.AddComponent(both, Blocking, BlockingComponent{})
+
+	positionOnly := world.NewEntity()
+	world.AddComponent(positionOnly, Position, PositionComponent{})
+
+	matches := world.Query(Position, Blocking)
+
+	if len(matches) != 1 || matches[0] != both {
+		t.Fatalf("Query(Position, Blocking) = %v, want [%d]", matches, both)
+	}
+}
+
+func TestQueryWithNoComponentTypesReturnsEmpty(t *testing.T) {
+	world := NewWorld()
+	world.NewEntity()
+
+	if matches := world.Query(); len(matches) != 0 {
+		t.Fatalf("Query() = %v, want empty", matches)
+	}
+}
@@ -0,0 +1,126 @@
+package persist
+
+import (
+	"dungeon"
+	"ecs"
+	"encoding/gob"
+	"gamemap"
+	"os"
+	"path/filepath"
+	"ui"
+)
+
+const saveDirName = "bearrogue"
+const saveFileName = "save.gob"
+
+// entitySnapshot is one entity's full component bag, keyed by ComponentType so it can be handed
+// straight to ecs.World.Restore.
+type entitySnapshot struct {
+	ID         ecs.EntityID
+	Components map[ecs.ComponentType]ecs.Component
+}
+
+// SaveFile is everything needed to resume a game exactly where it was left off.
+type SaveFile struct {
+	Levels       []*gamemap.Map
+	Depth        int
+	Entities     []entitySnapshot
+	NextEntityID ecs.EntityID
+	Messages     []ui.Message
+	CameraX      int
+	CameraY      int
+	Seed         int64
+}
+
+// SavePath returns the OS-appropriate path save files are read from and written to, creating its
+// parent directory if necessary.
+func SavePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, saveDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, saveFileName), nil
+}
+
+// Exists reports whether a save file is present, so the game can offer a "Continue" option at
+// startup.
+func Exists() bool {
+	path, err := SavePath()
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Save writes the full game state to the save file, overwriting anything already there.
+func Save(world *ecs.World, gameDungeon *dungeon.Dungeon, messages []ui.Message, cameraX, cameraY int, seed int64) error {
+	path, err := SavePath()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entities := make([]entitySnapshot, 0)
+	for _, id := range world.AllEntities() {
+		entities = append(entities, entitySnapshot{ID: id, Components: world.Components(id)})
+	}
+
+	save := SaveFile{
+		Levels:       gameDungeon.Levels,
+		Depth:        gameDungeon.Depth,
+		Entities:     entities,
+		NextEntityID: world.NextID(),
+		Messages:     messages,
+		CameraX:      cameraX,
+		CameraY:      cameraY,
+		Seed:         seed,
+	}
+
+	return gob.NewEncoder(file).Encode(save)
+}
+
+// Load reads the save file back into a SaveFile. Callers are responsible for rebuilding the
+// World, Dungeon, MessageLog, and camera from its contents.
+func Load() (*SaveFile, error) {
+	path, err := SavePath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var save SaveFile
+	if err := gob.NewDecoder(file).Decode(&save); err != nil {
+		return nil, err
+	}
+
+	return &save, nil
+}
+
+// Restore rebuilds a World's entities from the save file's snapshots.
+func (s *SaveFile) Restore(world *ecs.World) {
+	entities := make(map[ecs.EntityID]map[ecs.ComponentType]ecs.Component)
+	for _, entity := range s.Entities {
+		entities[entity.ID] = entity.Components
+	}
+
+	world.Restore(entities)
+	world.SetNextID(s.NextEntityID)
+}
@@ -3,10 +3,25 @@ package main
 import (
 	blt "bearlibterminal"
 	"camera"
-	"entity"
+	"combat"
+	"dungeon"
+	"ecs"
 	"fov"
 	"gamemap"
+	"math/rand"
+	"persist"
 	"strconv"
+	"time"
+	"ui"
+)
+
+// InputMode selects which handler consumes the next key press.
+type InputMode int
+
+const (
+	ModeNormal InputMode = iota
+	ModeTargeting
+	ModeHistory
 )
 
 const (
@@ -22,12 +37,28 @@ const (
 )
 
 var (
-	player *entity.GameEntity
-	entities []*entity.GameEntity
+	world *ecs.World
+	playerID ecs.EntityID
+	gameDungeon *dungeon.Dungeon
 	gameMap *gamemap.Map
+	currentDepth int
 	gameCamera *camera.GameCamera
 	fieldOfView *fov.FieldOfVision
-	messageLog []string
+	messageLog *ui.MessageLog
+	turnCount int
+	historyOffset int
+
+	inputMode InputMode
+	targeter *ui.Targeter
+	rngSeed int64
+	gameOver bool
+
+	movementSystem ecs.MovementSystem
+	randomMovementSystem ecs.RandomMovementSystem
+	meleeAISystem ecs.MeleeAISystem
+	combatSystem ecs.CombatSystem
+	deathSystem ecs.DeathSystem
+	renderSystem ecs.RenderSystem
 )
 
 func init() {
@@ -47,18 +78,34 @@ func init() {
 	blt.Set(window + "; " + font)
 	blt.Clear()
 
-	// Create a player Entity and an NPC entity, and add them to our slice of Entities
-	player = &entity.GameEntity{X: 1, Y: 1, Layer: 1, Char: "@", Color: "white"}
-	npc := &entity.GameEntity{X: 10, Y: 10, Layer: 0, Char: "N", Color: "red"}
-	entities = append(entities, player, npc)
-
-	// Create a GameMap, and initialize it (and set the player position within it, for now)
-	gameMap = &gamemap.Map{Width: MapWidth, Height: MapHeight}
-	gameMap.InitializeMap()
-
-	playerX, playerY := gameMap.GenerateCavern()
-	player.X = playerX
-	player.Y = playerY
+	rngSeed = time.Now().UnixNano()
+	rand.Seed(rngSeed)
+
+	// Create the Dungeon and descend into its first level
+	gameDungeon = dungeon.NewDungeon(MapWidth, MapHeight)
+	var playerX, playerY int
+	var firstVisit bool
+	gameMap, playerX, playerY, firstVisit = gameDungeon.Descend()
+	currentDepth = gameDungeon.Depth
+
+	// Stand up the ECS world and register a player entity as a component bag, rather than
+	// poking fields on a GameEntity directly.
+	world = ecs.NewWorld()
+
+	playerID = world.NewEntity()
+	world.AddComponent(playerID, ecs.Player, ecs.PlayerComponent{})
+	world.AddComponent(playerID, ecs.Position, ecs.PositionComponent{X: playerX, Y: playerY})
+	world.AddComponent(playerID, ecs.Appearance, ecs.AppearanceComponent{Color: "white", Character: "@", Layer: 1, Name: "you"})
+	world.AddComponent(playerID, ecs.Movement, ecs.MovementComponent{})
+	world.AddComponent(playerID, ecs.Controllable, ecs.ControllableComponent{})
+	world.AddComponent(playerID, ecs.HitPoint, ecs.HitPointComponent{Hp: 20, MaxHP: 20})
+	world.AddComponent(playerID, ecs.Attacker, ecs.AttackerComponent{Attack: 3, Defense: 1})
+	world.AddComponent(playerID, ecs.Killable, ecs.KillableComponent{Character: "%", Color: "dark red", Name: "your corpse"})
+	world.AddComponent(playerID, ecs.Level, ecs.LevelComponent{Depth: currentDepth})
+
+	if firstVisit {
+		spawnLevel(currentDepth)
+	}
 
 	// Initialize a camera object
 	gameCamera = &camera.GameCamera{X: 1, Y:1, Width: ViewAreaX, Height: ViewAreaY}
@@ -68,13 +115,26 @@ func init() {
 	fieldOfView.Initialize()
 	fieldOfView.SetTorchRadius(500)
 
-	messageLog = make([]string, 0)
-	sendMessage("You find yourself in the caverns of eternal sadness...you start to feel a little more sad.")
+	movementSystem = ecs.MovementSystem{GameMap: gameMap, Depth: currentDepth}
+	randomMovementSystem = ecs.RandomMovementSystem{GameMap: gameMap, Depth: currentDepth}
+	meleeAISystem = ecs.MeleeAISystem{GameMap: gameMap, Depth: currentDepth}
+	combatSystem = ecs.CombatSystem{Log: logAttack}
+	deathSystem = ecs.DeathSystem{}
+	renderSystem = ecs.RenderSystem{GameMap: gameMap, GameCamera: gameCamera, Depth: currentDepth}
+
+	messageLog = &ui.MessageLog{}
+	messageLog.InitMessages(99)
+	sendMessage("You find yourself in the caverns of eternal sadness...you start to feel a little more sad.", ui.CatFlavor)
+
+	inputMode = ModeNormal
+	targeter = &ui.Targeter{}
 }
-	
+
 func main() {
 	// Main game loop
 
+	offerContinue()
+
 	renderAll()
 	printMessages()
 
@@ -83,14 +143,11 @@ func main() {
 
 		key := blt.Read()
 
-		// Clear each Entity off the screen
-		for _, e := range entities {
-			mapX, mapY := gameCamera.ToCameraCoordinates(e.X, e.Y)
-			e.Clear(mapX, mapY)
-		}
-
 		if key != blt.TK_CLOSE {
-			handleInput(key, player)
+			acted := handleInput(key)
+			if acted {
+				advanceWorld()
+			}
 		} else {
 			break
 		}
@@ -102,8 +159,47 @@ func main() {
 	blt.Close()
 }
 
-func handleInput(key int, player *entity.GameEntity) {
-	// Handle basic character movement in the four main directions
+func handleInput(key int) bool {
+	// Once the player has died, stop reacting to anything but the window close the main loop
+	// already handles - no more movement, targeting, or history browsing.
+	if gameOver {
+		return false
+	}
+
+	// Dispatch to whichever handler owns the current input mode.
+	switch inputMode {
+	case ModeTargeting:
+		return handleTargetingInput(key)
+	case ModeHistory:
+		return handleHistoryInput(key)
+	default:
+		return handleMovementInput(key)
+	}
+}
+
+// handleHistoryInput scrolls the full message history while it is open, and closes it again on
+// Escape or Enter.
+func handleHistoryInput(key int) bool {
+	switch key {
+	case blt.TK_PAGEUP:
+		historyOffset++
+	case blt.TK_PAGEDOWN:
+		if historyOffset > 0 {
+			historyOffset--
+		}
+	case blt.TK_ENTER, blt.TK_ESCAPE, blt.TK_M:
+		inputMode = ModeNormal
+		historyOffset = 0
+		ui.ClearHistory(10, 2, WindowSizeX-20, ViewAreaY-4)
+	}
+
+	return false
+}
+
+func handleMovementInput(key int) bool {
+	// Handle basic character movement in the four main directions. Movement is not applied
+	// directly here - it is recorded as a MoveIntentComponent and resolved by MovementSystem, so
+	// the rest of the game loop never has to reach into entity state by hand.
 
 	var (
 		dx, dy int
@@ -118,33 +214,418 @@ func handleInput(key int, player *entity.GameEntity) {
 		dx, dy = 0, -1
 	case blt.TK_DOWN:
 		dx, dy = 0, 1
+	case blt.TK_PERIOD:
+		useStairs(gamemap.TileStairsDown)
+		return false
+	case blt.TK_COMMA:
+		useStairs(gamemap.TileStairsUp)
+		return false
+	case blt.TK_X, blt.TK_T:
+		enterTargetingMode()
+		return false
+	case blt.TK_M:
+		inputMode = ModeHistory
+		historyOffset = 0
+		return false
+	case blt.TK_F5:
+		saveGame()
+		return false
+	case blt.TK_F9:
+		loadGame()
+		return false
+	default:
+		return false
 	}
 
-	// Check to ensure that the tile the player is trying to move in to is a valid move (not blocked)
-	if !gameMap.IsBlocked(player.X + dx, player.Y + dy) {
-		player.Move(dx, dy)
+	if defenderID, ok := blockingEntityAt(dx, dy); ok {
+		bumpAttack(defenderID)
+		return true
 	}
+
+	world.AddComponent(playerID, ecs.MoveIntent, ecs.MoveIntentComponent{DX: dx, DY: dy})
+	return true
 }
 
-func renderEntities() {
-	// Draw every Entity present in the game. This gets called on each iteration of the game loop.
-	for _, e := range entities {
-		if e != player {
-			cameraX, cameraY := gameCamera.ToCameraCoordinates(e.X, e.Y)
-			if gameMap.Tiles[e.X][e.Y].Visible {
-				e.Draw(cameraX, cameraY)
-			}
+// blockingEntityAt reports the entity, if any, that occupies the tile (dx, dy) away from the
+// player and would block movement into it - i.e. something the player should bump-attack rather
+// than walk into.
+func blockingEntityAt(dx, dy int) (ecs.EntityID, bool) {
+	playerPositionComponent, _ := world.Get(playerID, ecs.Position)
+	playerPosition := playerPositionComponent.(ecs.PositionComponent)
+	destX, destY := playerPosition.X+dx, playerPosition.Y+dy
+
+	for _, id := range world.Query(ecs.Position, ecs.Blocking, ecs.HitPoint) {
+		if levelComponent, ok := world.Get(id, ecs.Level); ok && levelComponent.(ecs.LevelComponent).Depth != currentDepth {
+			continue
+		}
+
+		positionComponent, _ := world.Get(id, ecs.Position)
+		pos := positionComponent.(ecs.PositionComponent)
+		if pos.X == destX && pos.Y == destY {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// logAttack reports an AI-initiated attack (resolved by ecs.CombatSystem) in the message log.
+func logAttack(attacker, defender ecs.EntityID, damage int) {
+	attackerAppearanceComponent, _ := world.Get(attacker, ecs.Appearance)
+	attackerAppearance := attackerAppearanceComponent.(ecs.AppearanceComponent)
+
+	if defender == playerID {
+		sendMessage("The "+attackerAppearance.Name+" hits you for "+strconv.Itoa(damage)+" damage.", ui.CatCombat)
+		return
+	}
+
+	defenderAppearanceComponent, _ := world.Get(defender, ecs.Appearance)
+	defenderAppearance := defenderAppearanceComponent.(ecs.AppearanceComponent)
+	sendMessage("The "+attackerAppearance.Name+" hits the "+defenderAppearance.Name+" for "+strconv.Itoa(damage)+" damage.", ui.CatCombat)
+}
+
+// bumpAttack resolves the player attacking defenderID and reports the result in the message log.
+func bumpAttack(defenderID ecs.EntityID) {
+	appearanceComponent, _ := world.Get(defenderID, ecs.Appearance)
+	appearance := appearanceComponent.(ecs.AppearanceComponent)
+
+	damage, killed := combat.Attack(world, playerID, defenderID)
+
+	if killed {
+		sendMessage("You strike down the "+appearance.Name+".", ui.CatCombat)
+	} else {
+		sendMessage("You hit the "+appearance.Name+" for "+strconv.Itoa(damage)+" damage.", ui.CatCombat)
+	}
+}
+
+// enterTargetingMode switches to examine/target mode, starting the crosshair on the player and
+// seeding it with every visible hostile as a Tab-cycle candidate.
+func enterTargetingMode() {
+	playerPositionComponent, _ := world.Get(playerID, ecs.Position)
+	playerPosition := playerPositionComponent.(ecs.PositionComponent)
+
+	candidates := make([]ui.TargetCandidate, 0)
+	for _, id := range world.Query(ecs.Position, ecs.BasicMeleeAI) {
+		if levelComponent, ok := world.Get(id, ecs.Level); ok && levelComponent.(ecs.LevelComponent).Depth != currentDepth {
+			continue
 		}
+
+		positionComponent, _ := world.Get(id, ecs.Position)
+		pos := positionComponent.(ecs.PositionComponent)
+		if gameMap.Tiles[pos.X][pos.Y].Visible {
+			candidates = append(candidates, ui.TargetCandidate{X: pos.X, Y: pos.Y})
+		}
+	}
+
+	targeter.Activate(playerPosition.X, playerPosition.Y, candidates)
+	inputMode = ModeTargeting
+}
+
+// handleTargetingInput drives the crosshair while examine/target mode is active.
+func handleTargetingInput(key int) bool {
+	switch key {
+	case blt.TK_RIGHT:
+		targeter.Move(1, 0, gameMap.Width, gameMap.Height)
+	case blt.TK_LEFT:
+		targeter.Move(-1, 0, gameMap.Width, gameMap.Height)
+	case blt.TK_UP:
+		targeter.Move(0, -1, gameMap.Width, gameMap.Height)
+	case blt.TK_DOWN:
+		targeter.Move(0, 1, gameMap.Width, gameMap.Height)
+	case blt.TK_TAB:
+		targeter.CycleTarget()
+	case blt.TK_ENTER:
+		sendMessage("You settle on a target.", ui.CatSystem)
+		targeter.Deactivate()
+		targeter.ClearOverlay(0, 0, WindowSizeX, WindowSizeY)
+		inputMode = ModeNormal
+	case blt.TK_ESCAPE:
+		targeter.Deactivate()
+		targeter.ClearOverlay(0, 0, WindowSizeX, WindowSizeY)
+		inputMode = ModeNormal
 	}
 
-	cameraX, cameraY := gameCamera.ToCameraCoordinates(player.X, player.Y)
-	player.Draw(cameraX, cameraY)
+	return false
+}
+
+// targetDescription builds the lines shown in the info panel for whatever is currently under
+// the crosshair: the tile's terrain, any entity standing on it, its combat stats if it has any,
+// and whether the cell is currently in the player's field of view.
+func targetDescription() []string {
+	lines := make([]string, 0)
+
+	tile := gameMap.Tiles[targeter.X][targeter.Y]
+	if tile.IsWall() {
+		lines = append(lines, "A rough stone wall.")
+	} else {
+		lines = append(lines, "Open cavern floor.")
+	}
+
+	for _, id := range world.Query(ecs.Position, ecs.Appearance) {
+		if levelComponent, ok := world.Get(id, ecs.Level); ok && levelComponent.(ecs.LevelComponent).Depth != currentDepth {
+			continue
+		}
+
+		positionComponent, _ := world.Get(id, ecs.Position)
+		pos := positionComponent.(ecs.PositionComponent)
+		if pos.X != targeter.X || pos.Y != targeter.Y {
+			continue
+		}
+
+		appearanceComponent, _ := world.Get(id, ecs.Appearance)
+		appearance := appearanceComponent.(ecs.AppearanceComponent)
+		lines = append(lines, appearance.Name)
+
+		if hpComponent, ok := world.Get(id, ecs.HitPoint); ok {
+			hp := hpComponent.(ecs.HitPointComponent)
+			lines = append(lines, "HP: "+strconv.Itoa(hp.Hp)+"/"+strconv.Itoa(hp.MaxHP))
+		}
+
+		if attackerComponent, ok := world.Get(id, ecs.Attacker); ok {
+			attacker := attackerComponent.(ecs.AttackerComponent)
+			lines = append(lines, "Attack: "+strconv.Itoa(attacker.Attack)+" Defense: "+strconv.Itoa(attacker.Defense))
+		}
+	}
+
+	if tile.Visible {
+		lines = append(lines, "(in view)")
+	} else {
+		lines = append(lines, "(out of view)")
+	}
+
+	return lines
+}
+
+// offerContinue checks for an existing save file at startup and, if one is found, asks the
+// player whether to resume it before the normal game loop begins.
+func offerContinue() {
+	if !persist.Exists() {
+		return
+	}
+
+	blt.Clear()
+	blt.Print(1, 1, "A saved game was found.")
+	blt.Print(1, 2, "Press C to continue it, or any other key to start fresh.")
+	blt.Refresh()
+
+	if blt.Read() == blt.TK_C {
+		loadGame()
+	}
+}
+
+// saveGame writes the full game state to disk and reports success or failure in the message log.
+func saveGame() {
+	err := persist.Save(world, gameDungeon, messageLog.Messages(), gameCamera.X, gameCamera.Y, rngSeed)
+	if err != nil {
+		sendMessage("Failed to save the game.", ui.CatWarning)
+		return
+	}
+
+	sendMessage("Game saved.", ui.CatSystem)
+}
+
+// loadGame restores the full game state from disk, replacing everything currently in play.
+func loadGame() {
+	save, err := persist.Load()
+	if err != nil {
+		sendMessage("No saved game could be loaded.", ui.CatWarning)
+		return
+	}
+
+	save.Restore(world)
+
+	gameDungeon.Levels = save.Levels
+	gameDungeon.Depth = save.Depth
+	gameMap = gameDungeon.CurrentLevel()
+	currentDepth = gameDungeon.Depth
+
+	for _, id := range world.Query(ecs.Player) {
+		playerID = id
+	}
+
+	messageLog.Restore(save.Messages, 99)
+
+	gameCamera.X, gameCamera.Y = save.CameraX, save.CameraY
+
+	rngSeed = save.Seed
+	rand.Seed(rngSeed)
+
+	fieldOfView.Initialize()
+	fieldOfView.SetTorchRadius(500)
+
+	movementSystem.GameMap = gameMap
+	movementSystem.Depth = currentDepth
+	randomMovementSystem.GameMap = gameMap
+	randomMovementSystem.Depth = currentDepth
+	meleeAISystem.GameMap = gameMap
+	meleeAISystem.Depth = currentDepth
+	renderSystem.GameMap = gameMap
+	renderSystem.Depth = currentDepth
+
+	sendMessage("Game loaded.", ui.CatSystem)
+}
+
+// autosave silently persists the game on stair descent, so a crash doesn't wipe progress made
+// getting to a new level.
+func autosave() {
+	_ = persist.Save(world, gameDungeon, messageLog.Messages(), gameCamera.X, gameCamera.Y, rngSeed)
+}
+
+// useStairs moves the player to the next or previous dungeon level if they are standing on the
+// matching stairs tile. It does not consume a turn - descending/ascending repositions the player
+// but the AI does not get to act on the level they just left.
+func useStairs(tile gamemap.TileType) {
+	playerPositionComponent, _ := world.Get(playerID, ecs.Position)
+	playerPosition := playerPositionComponent.(ecs.PositionComponent)
+
+	if gameMap.Tiles[playerPosition.X][playerPosition.Y].TileType != tile {
+		sendMessage("There are no stairs here.", ui.CatWarning)
+		return
+	}
+
+	var entryX, entryY int
+
+	if tile == gamemap.TileStairsDown {
+		var firstVisit bool
+		gameMap, entryX, entryY, firstVisit = gameDungeon.Descend()
+		currentDepth = gameDungeon.Depth
+		if firstVisit {
+			spawnLevel(currentDepth)
+		}
+		sendMessage("You descend further into the caverns.", ui.CatSystem)
+		defer autosave()
+	} else {
+		level, x, y, ok := gameDungeon.Ascend()
+		if !ok {
+			sendMessage("You can't go any higher than this.", ui.CatWarning)
+			return
+		}
+		gameMap, entryX, entryY = level, x, y
+		currentDepth = gameDungeon.Depth
+		sendMessage("You climb back up the stairs.", ui.CatSystem)
+	}
+
+	playerPosition.X, playerPosition.Y = entryX, entryY
+	world.AddComponent(playerID, ecs.Position, playerPosition)
+	world.AddComponent(playerID, ecs.Level, ecs.LevelComponent{Depth: currentDepth})
+
+	movementSystem.GameMap = gameMap
+	movementSystem.Depth = currentDepth
+	randomMovementSystem.GameMap = gameMap
+	randomMovementSystem.Depth = currentDepth
+	meleeAISystem.GameMap = gameMap
+	meleeAISystem.Depth = currentDepth
+	renderSystem.GameMap = gameMap
+	renderSystem.Depth = currentDepth
+
+	fieldOfView.Initialize()
+	fieldOfView.SetTorchRadius(500)
+	gameCamera.MoveCamera(entryX, entryY, gameMap.Width, gameMap.Height)
+}
+
+// spawnLevel populates a freshly generated depth with monsters, scaling both the count and their
+// stats with depth so the dungeon gets more dangerous the deeper the player goes. It is only
+// called the first time a given depth is generated - revisiting a level keeps whatever is left of
+// its original population (including corpses and anything that fled off-screen).
+func spawnLevel(depth int) {
+	level := gameDungeon.CurrentLevel()
+
+	monsterCount := 2 + depth
+	if monsterCount > 10 {
+		monsterCount = 10
+	}
+
+	for i := 0; i < monsterCount; i++ {
+		x, y := randomOpenTile(level)
+
+		if rand.Intn(3) == 0 {
+			spawnWanderer(x, y, depth)
+		} else {
+			spawnGoblin(x, y, depth)
+		}
+	}
+}
+
+// randomOpenTile picks a random unblocked tile on level, retrying until one is found. The map is
+// guaranteed to have at least as much open floor as its generator placed the stairs on, so this
+// always terminates in practice.
+func randomOpenTile(level *gamemap.Map) (x, y int) {
+	for {
+		x, y = rand.Intn(level.Width), rand.Intn(level.Height)
+		if !level.IsBlocked(x, y) {
+			return x, y
+		}
+	}
+}
+
+// spawnWanderer creates a harmless, non-blocking NPC that wanders randomly around the level.
+func spawnWanderer(x, y, depth int) {
+	npcID := world.NewEntity()
+	world.AddComponent(npcID, ecs.Position, ecs.PositionComponent{X: x, Y: y})
+	world.AddComponent(npcID, ecs.Appearance, ecs.AppearanceComponent{Color: "red", Character: "N", Layer: 0, Name: "wanderer"})
+	world.AddComponent(npcID, ecs.Movement, ecs.MovementComponent{})
+	world.AddComponent(npcID, ecs.RandomMovement, ecs.RandomMovementComponent{})
+	world.AddComponent(npcID, ecs.Level, ecs.LevelComponent{Depth: depth})
+}
+
+// spawnGoblin creates a blocking, melee-AI-driven goblin whose Attack/Defense/HP scale with
+// depth, so later levels are meaningfully harder than the first.
+func spawnGoblin(x, y, depth int) {
+	attack := 2 + depth/2
+	defense := depth / 3
+	maxHP := 8 + depth*2
+
+	goblinID := world.NewEntity()
+	world.AddComponent(goblinID, ecs.Position, ecs.PositionComponent{X: x, Y: y})
+	world.AddComponent(goblinID, ecs.Appearance, ecs.AppearanceComponent{Color: "green", Character: "g", Layer: 0, Name: "goblin"})
+	world.AddComponent(goblinID, ecs.Blocking, ecs.BlockingComponent{})
+	world.AddComponent(goblinID, ecs.BasicMeleeAI, ecs.BasicMeleeAIComponent{})
+	world.AddComponent(goblinID, ecs.HitPoint, ecs.HitPointComponent{Hp: maxHP, MaxHP: maxHP})
+	world.AddComponent(goblinID, ecs.Attacker, ecs.AttackerComponent{Attack: attack, Defense: defense})
+	world.AddComponent(goblinID, ecs.Killable, ecs.KillableComponent{Character: "%", Color: "dark red", Name: "goblin corpse"})
+	world.AddComponent(goblinID, ecs.Level, ecs.LevelComponent{Depth: depth})
+}
+
+func advanceWorld() {
+	// Drive every system for a single turn. MovementSystem runs first so the player's intent is
+	// resolved before the AI reacts to the player's new position.
+	turnCount++
+	movementSystem.Update(world, 1)
+	randomMovementSystem.Update(world, 1)
+	meleeAISystem.Update(world, 1)
+	combatSystem.Update(world, 1)
+	deathSystem.Update(world, 1)
+	checkPlayerDeath()
+}
+
+// checkPlayerDeath ends the run the first time the player's HP drops to zero or below.
+// DeathSystem has already swapped the player's Appearance to their corpse glyph by the time this
+// runs - this just reports it and freezes input, since DeathSystem has no notion of a "game over".
+func checkPlayerDeath() {
+	if gameOver {
+		return
+	}
+
+	hpComponent, ok := world.Get(playerID, ecs.HitPoint)
+	if !ok {
+		return
+	}
+
+	if hpComponent.(ecs.HitPointComponent).Hp > 0 {
+		return
+	}
+
+	gameOver = true
+	sendMessage("You have died.", ui.CatWarning)
 }
 
 func renderMap() {
 	// Render the game map. If a tile is blocked and blocks sight, draw a '#', if it is not blocked, and does not block
 	// sight, draw a '.'
 
+	playerPositionComponent, _ := world.Get(playerID, ecs.Position)
+	playerPosition := playerPositionComponent.(ecs.PositionComponent)
+
 	// First, set the entire map to not visible. We'll decide what is visible based on the torch radius.
 	// In the process, clear every Tile on the map as well
 	for x := 0; x < gameMap.Width; x++ {
@@ -155,7 +636,7 @@ func renderMap() {
 	}
 
 	// Next figure out what is visible to the player, and what is not.
-	fieldOfView.RayCast(player.X, player.Y, gameMap)
+	fieldOfView.RayCast(playerPosition.X, playerPosition.Y, gameMap)
 
 	// Now draw each tile that should appear on the screen, if its visible, or explored
 	for x := 0; x < gameCamera.Width; x++ {
@@ -186,47 +667,33 @@ func renderMap() {
 func renderAll() {
 	// Convenience function to render all entities, followed by rendering the game map
 
+	playerPositionComponent, _ := world.Get(playerID, ecs.Position)
+	playerPosition := playerPositionComponent.(ecs.PositionComponent)
+
 	// Before anything is rendered, update the camera position, so it is centered (if possible) on the player
 	// Only things within the cameras viewport will be drawn to the screen
-	gameCamera.MoveCamera(player.X, player.Y, MapWidth, MapHeight)
+	gameCamera.MoveCamera(playerPosition.X, playerPosition.Y, MapWidth, MapHeight)
 
 	renderMap()
-	renderEntities()
-}
+	renderSystem.Update(world, 0)
 
-func sendMessage(message string) {
-	// Prepend the message onto the messageLog slice
-	if len(messageLog) >= 99 {
-		// Throw away any messages that exceed our total queue size
-		messageLog = messageLog[:len(messageLog)-1]
+	if targeter.Active {
+		cameraX, cameraY := gameCamera.ToCameraCoordinates(targeter.X, targeter.Y)
+		targeter.DrawCrosshair(cameraX, cameraY)
+		targeter.DrawInfoPanel(ViewAreaX+1, 0, WindowSizeX-ViewAreaX-1, ViewAreaY, targetDescription())
 	}
-	messageLog = append([]string{message}, messageLog...)
 }
 
-func clearMessages() {
-	// Clear the message area, so our messages do not overlap
-	blt.ClearArea(0, ViewAreaY, WindowSizeX, WindowSizeY - ViewAreaY)
+func sendMessage(message string, category ui.MessageCategory) {
+	messageLog.SendMessage(message, category, turnCount)
 }
 
 func printMessages() {
 	// Print the latest five messages from the messageLog. These will be printed in reverse order (newest at the top),
 	// to make it appear they are scrolling down the screen
-	clearMessages()
-
-	toShow := 0
+	messageLog.PrintMessages(ViewAreaY, WindowSizeX, WindowSizeY)
 
-	if len(messageLog) <= 5 {
-		// Just loop through the messageLog, printing them in reverse order
-		toShow = len(messageLog)
-	} else {
-		// If we have more than 5 messages stored, just show the five most recent
-		toShow = 5
-	}
-
-	blt.Color(blt.ColorFromName("white"))
-	blt.Layer(1)
-	for i := toShow; i > 0; i-- {
-		blt.Print(1, (ViewAreaY - 1) + i, messageLog[i - 1])
+	if inputMode == ModeHistory {
+		messageLog.ShowHistory(10, 2, WindowSizeX-20, ViewAreaY-4, historyOffset)
 	}
 }
-
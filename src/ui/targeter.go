@@ -0,0 +1,92 @@
+package ui
+
+import (
+	blt "bearlibterminal"
+)
+
+// TargetCandidate is a single cell Tab-cycling can land on - typically a visible hostile entity.
+type TargetCandidate struct {
+	X, Y int
+}
+
+// Targeter owns the crosshair used by examine/target mode. It only knows about map
+// coordinates and candidate cells - it has no idea what a GameEntity or a Tile is, so callers
+// are responsible for feeding it candidates and for turning its current position back into a
+// description of what is there.
+type Targeter struct {
+	Active bool
+	X, Y int
+	candidates []TargetCandidate
+	candidateIndex int
+}
+
+// Activate turns on the crosshair, starting it at (originX, originY) - normally the player's
+// position.
+func (t *Targeter) Activate(originX, originY int, candidates []TargetCandidate) {
+	t.Active = true
+	t.X, t.Y = originX, originY
+	t.candidates = candidates
+	t.candidateIndex = -1
+}
+
+// Deactivate turns the crosshair off.
+func (t *Targeter) Deactivate() {
+	t.Active = false
+	t.candidates = nil
+	t.candidateIndex = -1
+}
+
+// Move shifts the crosshair by (dx, dy), clamped to the map bounds.
+func (t *Targeter) Move(dx, dy, mapWidth, mapHeight int) {
+	newX, newY := t.X+dx, t.Y+dy
+	if newX < 0 || newX >= mapWidth || newY < 0 || newY >= mapHeight {
+		return
+	}
+
+	t.X, t.Y = newX, newY
+}
+
+// CycleTarget advances the crosshair to the next candidate entity, wrapping back to the first
+// once the last is reached. If there are no candidates, this is a no-op.
+func (t *Targeter) CycleTarget() {
+	if len(t.candidates) == 0 {
+		return
+	}
+
+	t.candidateIndex = (t.candidateIndex + 1) % len(t.candidates)
+	candidate := t.candidates[t.candidateIndex]
+	t.X, t.Y = candidate.X, candidate.Y
+}
+
+// DrawCrosshair renders the crosshair at its current position, translated to screen/camera
+// coordinates by the caller.
+func (t *Targeter) DrawCrosshair(cameraX, cameraY int) {
+	blt.Layer(2)
+	blt.Color(blt.ColorFromName("yellow"))
+	blt.Print(cameraX, cameraY, "X")
+}
+
+// DrawInfoPanel renders a description panel next to the message log, one line per entry in
+// lines. It clears the area first and draws no border. Callers build the lines (tile terrain, entity name/HP, FoV status) since the
+// Targeter itself has no access to the map or the ecs registry.
+func (t *Targeter) DrawInfoPanel(x, y, width, height int, lines []string) {
+	blt.Layer(2)
+	blt.Color(blt.ColorFromName("white"))
+
+	blt.ClearArea(x, y, width, height)
+
+	for i, line := range lines {
+		if i >= height {
+			break
+		}
+		blt.Print(x, y+i, line)
+	}
+}
+
+// ClearOverlay wipes layer 2 over the crosshair and info panel area. Callers should invoke this
+// once when dismissing targeting mode, since Deactivate only flips Active off and nothing else
+// would otherwise erase the last-drawn crosshair/panel glyphs.
+func (t *Targeter) ClearOverlay(panelX, panelY, panelWidth, panelHeight int) {
+	blt.Layer(2)
+	blt.ClearArea(panelX, panelY, panelWidth, panelHeight)
+}
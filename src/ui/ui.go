@@ -2,49 +2,165 @@ package ui
 
 import (
 	blt "bearlibterminal"
+	"strconv"
 )
 
+// MessageCategory groups messages so they can be color-coded and so the history viewer can be
+// extended later to filter by kind.
+type MessageCategory int
+
+const (
+	CatSystem MessageCategory = iota
+	CatCombat
+	CatFlavor
+	CatWarning
+)
+
+// categoryColor maps a MessageCategory to the blt color name it should be printed in.
+func categoryColor(category MessageCategory) string {
+	switch category {
+	case CatCombat:
+		return "red"
+	case CatWarning:
+		return "orange"
+	case CatFlavor:
+		return "light blue"
+	default:
+		return "white"
+	}
+}
+
+// Message is a single entry in a MessageLog. Identical consecutive messages are collapsed into
+// one Message with an incrementing Count, rather than filling the log with repeats.
+type Message struct {
+	Text     string
+	Category MessageCategory
+	Count    int
+	Turn     int
+}
+
 type MessageLog struct {
-	messages []string
+	messages []Message
 	MaxLength int
 }
 
+// Messages returns every message currently held in the log, newest first, for serialization.
+func (ml *MessageLog) Messages() []Message {
+	return ml.messages
+}
+
+// Restore replaces the log's contents wholesale, used when loading a save file.
+func (ml *MessageLog) Restore(messages []Message, maxLength int) {
+	ml.messages = messages
+	ml.MaxLength = maxLength
+}
+
 func (ml *MessageLog) InitMessages(maxLength int) {
-	ml.messages = make([]string, maxLength)
+	ml.messages = make([]Message, 0, maxLength)
+	ml.MaxLength = maxLength
 }
 
-func (ml *MessageLog) SendMessage(message string) {
-	// Prepend the message onto the messageLog slice
+// SendMessage appends a message to the log, tagged with its category and the turn it happened
+// on. If it is identical to the most recent message, the existing entry's Count is bumped
+// instead of adding a new line.
+func (ml *MessageLog) SendMessage(text string, category MessageCategory, turn int) {
+	if len(ml.messages) > 0 {
+		last := &ml.messages[0]
+		if last.Text == text && last.Category == category {
+			last.Count++
+			last.Turn = turn
+			return
+		}
+	}
+
 	if len(ml.messages) >= ml.MaxLength {
-		// Throw away any messages that exceed our total queue size
 		ml.messages = ml.messages[:len(ml.messages)-1]
 	}
-	ml.messages = append([]string{message}, ml.messages...)
+
+	ml.messages = append([]Message{{Text: text, Category: category, Count: 1, Turn: turn}}, ml.messages...)
 }
 
+// PrintMessages prints the five most recent messages, newest on top, color-coded by category.
+// A message with Count > 1 is suffixed with "(xN)".
 func (ml *MessageLog) PrintMessages(viewAreaY, windowSizeX, windowSizeY int) {
-	// Print the latest five messages from the messageLog. These will be printed in reverse order (newest at the top),
-	// to make it appear they are scrolling down the screen
 	clearMessages(viewAreaY, windowSizeX, windowSizeY)
 
-	toShow := 0
-
-	if len(ml.messages) <= 5 {
-		// Just loop through the messageLog, printing them in reverse order
-		toShow = len(ml.messages)
-	} else {
-		// If we have more than 5 messages stored, just show the five most recent
+	toShow := len(ml.messages)
+	if toShow > 5 {
 		toShow = 5
 	}
 
-	blt.Color(blt.ColorFromName("white"))
 	blt.Layer(1)
 	for i := toShow; i > 0; i-- {
-		blt.Print(1, (viewAreaY - 1) + i, ml.messages[i - 1])
+		message := ml.messages[i-1]
+		blt.Color(blt.ColorFromName(categoryColor(message.Category)))
+		blt.Print(1, (viewAreaY-1)+i, formatMessage(message))
+	}
+}
+
+// dimAfterTurns is how many turns old a message has to be, relative to the most recent message
+// in the log, before ShowHistory dims it to gray.
+const dimAfterTurns = 10
+
+// ShowHistory renders the entire message buffer in a bordered pane, offset messages down from
+// the most recent. Messages more than dimAfterTurns turns older than the newest one are dimmed,
+// so the eye is drawn to what's recent rather than what's merely near the top of the pane. The
+// caller is expected to re-invoke this as the player scrolls with PgUp/PgDn, adjusting offset.
+func (ml *MessageLog) ShowHistory(x, y, width, height, offset int) {
+	blt.Layer(3)
+	blt.Color(blt.ColorFromName("white"))
+
+	blt.ClearArea(x, y, width, height)
+
+	blt.Print(x, y, "+"+repeat("-", width-2)+"+")
+	blt.Print(x, y+height-1, "+"+repeat("-", width-2)+"+")
+
+	mostRecentTurn := 0
+	if len(ml.messages) > 0 {
+		mostRecentTurn = ml.messages[0].Turn
+	}
+
+	visible := height - 2
+	for row := 0; row < visible; row++ {
+		index := offset + row
+		if index >= len(ml.messages) {
+			break
+		}
+
+		message := ml.messages[index]
+		if mostRecentTurn-message.Turn > dimAfterTurns {
+			blt.Color(blt.ColorFromName("gray"))
+		} else {
+			blt.Color(blt.ColorFromName(categoryColor(message.Category)))
+		}
+
+		blt.Print(x+1, y+1+row, formatMessage(message))
 	}
 }
 
+// ClearHistory wipes layer 3, the history pane's layer. Callers should invoke this once when
+// leaving the history view, since nothing else ever clears that layer afterward.
+func ClearHistory(x, y, width, height int) {
+	blt.Layer(3)
+	blt.ClearArea(x, y, width, height)
+}
+
+func formatMessage(message Message) string {
+	if message.Count > 1 {
+		return message.Text + " (x" + strconv.Itoa(message.Count) + ")"
+	}
+	return message.Text
+}
+
+func repeat(s string, count int) string {
+	out := ""
+	for i := 0; i < count; i++ {
+		out += s
+	}
+	return out
+}
+
 func clearMessages(viewAreaY, windowSizeX, windowSizeY int) {
 	// Clear the message area, so our messages do not overlap
 	blt.ClearArea(0, viewAreaY, windowSizeX, windowSizeY - viewAreaY)
-}
\ No newline at end of file
+}